@@ -0,0 +1,350 @@
+package odfl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+//bol is the xml namespace for the BOL service
+var bol = "http://bol.ws.odfl.com"
+
+//BOLURLTest and BOLURLProd are the SOAP endpoints BOL requests are posted to
+//when a Client's BOLURL is unset - BOLURLTest unless TestMode is false.
+var (
+	BOLURLTest = "http://www.odfl.com/wsBOL_v1b/services/ODBOLSOAPTest"
+	BOLURLProd = "http://www.odfl.com/wsBOL_v1b/services/ODBOLSOAP"
+)
+
+//PaymentMethod is how freight charges on a BOL are to be billed
+type PaymentMethod string
+
+//valid PaymentMethod values
+const (
+	PaymentPrepaid    PaymentMethod = "P" //shipper pays
+	PaymentCollect    PaymentMethod = "C" //consignee pays
+	PaymentThirdParty PaymentMethod = "T" //a third party (BillTo) pays
+)
+
+//BOLParty is a shipper, consignee, or bill-to address on a BOL
+type BOLParty struct {
+	CompanyName   string `xml:"companyName" json:"companyName"`
+	AddressLine1  string `xml:"addressLine1" json:"addressLine1"`
+	AddressLine2  string `xml:"addressLine2" json:"addressLine2,omitempty"`
+	City          string `xml:"city" json:"city"`
+	StateProvince string `xml:"stateProvince" json:"stateProvince"` //two characters
+	PostalCode    string `xml:"postalCode" json:"postalCode"`
+	Country       string `xml:"country" json:"country"` //USA, CAN, or MEX
+	ContactFName  string `xml:"contactFName" json:"contactFName,omitempty"`
+	ContactLName  string `xml:"contactLName" json:"contactLName,omitempty"`
+	PhoneAreaCode string `xml:"phoneAreaCode" json:"phoneAreaCode,omitempty"`
+	PhoneNumber   string `xml:"phoneNumber" json:"phoneNumber,omitempty"`
+	PhoneExt      string `xml:"phoneExt" json:"phoneExt,omitempty"`
+	Email         string `xml:"email" json:"email,omitempty"`
+}
+
+//BOLReferenceNumber is a shipper/PO/SO/etc. reference number attached to a BOL
+type BOLReferenceNumber struct {
+	Type  string `xml:"type" json:"type"` //ex: "PO", "SO", "Customer Order"
+	Value string `xml:"value" json:"value"`
+}
+
+//BOLItem is one commodity/handling-unit line item on a BOL
+type BOLItem struct {
+	//required
+	HandlingUnits uint    `xml:"handlingUnits" json:"handlingUnits"`
+	UnitType      string  `xml:"unitType" json:"unitType"` //BDL, CRT, CTN, DRUM, SKID, OTH
+	Pieces        uint    `xml:"pieces" json:"pieces"`
+	Weight        float64 `xml:"weight" json:"weight"` //lbs
+	FreightClass  string  `xml:"freightClass" json:"freightClass"`
+	Description   string  `xml:"description" json:"description"`
+
+	//optional
+	NMFC   string  `xml:"nmfc" json:"nmfc,omitempty"`
+	Length float64 `xml:"length" json:"length,omitempty"` //inches
+	Width  float64 `xml:"width" json:"width,omitempty"`   //inches
+	Height float64 `xml:"height" json:"height,omitempty"` //inches
+
+	//required when Hazmat is true
+	Hazmat              bool   `xml:"hazmat" json:"hazmat"`
+	HazmatUNNumber      string `xml:"hazmatUNNumber" json:"hazmatUNNumber,omitempty"`         //ex: "UN1993"
+	HazmatPackingGroup  string `xml:"hazmatPackingGroup" json:"hazmatPackingGroup,omitempty"` //I, II, or III
+	HazmatTechnicalName string `xml:"hazmatTechnicalName" json:"hazmatTechnicalName,omitempty"`
+}
+
+//BOLAccessorials are the extra services being requested for a shipment
+type BOLAccessorials struct {
+	Liftgate         bool `xml:"liftgate" json:"liftgate"`
+	Residential      bool `xml:"residential" json:"residential"`
+	InsideDelivery   bool `xml:"insideDelivery" json:"insideDelivery"`
+	LimitedAccess    bool `xml:"limitedAccess" json:"limitedAccess"`
+	SortAndSegregate bool `xml:"sortAndSegregate" json:"sortAndSegregate"`
+}
+
+//BOL is the main body of a bill of lading generation/submission request
+type BOL struct {
+	XMLName xml.Name `xml:"soapenv:Envelope"`
+
+	SoapenvAttr string `xml:"xmlns:soapenv,attr"`
+	BolAttr     string `xml:"xmlns:bol,attr"`
+
+	//required
+	ShipDate      Date          `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>shipDate"`
+	Shipper       BOLParty      `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>shipper"`
+	Consignee     BOLParty      `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>consignee"`
+	PaymentMethod PaymentMethod `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>paymentMethod"`
+	Items         []BOLItem     `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>items>item"`
+
+	//optional
+	BillTo              *BOLParty            `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>billTo"`
+	ReferenceNumbers    []BOLReferenceNumber `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>referenceNumbers>referenceNumber"`
+	SpecialInstructions string               `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>specialInstructions"`
+	Accessorials        BOLAccessorials      `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>accessorials"`
+	CODAmount           float64              `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>codAmount"`
+	CODPaymentMethod    string               `xml:"soapenv:Header>soapenv:Body>bol:bolRequest>codPaymentMethod"` //check, cash, etc.
+}
+
+//Validate checks a BOL for the required-field rules ODFL enforces per
+//payment method and per hazmat presence, so callers get a structured error
+//before hitting the wire instead of a rejected submission.
+func (b *BOL) Validate() error {
+	var problems []string
+
+	if b.PaymentMethod == "" {
+		problems = append(problems, "paymentMethod is required")
+	}
+
+	if b.PaymentMethod == PaymentThirdParty {
+		if b.BillTo == nil {
+			problems = append(problems, "billTo is required when paymentMethod is third-party")
+		} else if b.BillTo.CompanyName == "" || b.BillTo.AddressLine1 == "" || b.BillTo.City == "" ||
+			b.BillTo.StateProvince == "" || b.BillTo.PostalCode == "" {
+			problems = append(problems, "billTo companyName, addressLine1, city, stateProvince, and postalCode are required when paymentMethod is third-party")
+		}
+	}
+
+	if b.CODAmount > 0 && b.CODPaymentMethod == "" {
+		problems = append(problems, "codPaymentMethod is required when codAmount is set")
+	}
+
+	if len(b.Items) == 0 {
+		problems = append(problems, "at least one item is required")
+	}
+
+	for i, item := range b.Items {
+		if item.Hazmat {
+			if item.HazmatUNNumber == "" {
+				problems = append(problems, errors.Errorf("items[%d].hazmatUNNumber is required when hazmat is true", i).Error())
+			}
+			if item.HazmatPackingGroup == "" {
+				problems = append(problems, errors.Errorf("items[%d].hazmatPackingGroup is required when hazmat is true", i).Error())
+			}
+			if item.HazmatTechnicalName == "" {
+				problems = append(problems, errors.Errorf("items[%d].hazmatTechnicalName is required when hazmat is true", i).Error())
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("odfl: BOL failed validation: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+//BOLResponse is the parsed result of a BOL submission
+type BOLResponse struct {
+	ProNumber string        `json:"proNumber" xml:"proNumber"`
+	Errors    []PickupError `json:"errors" xml:"errors>error"`
+
+	//documentBase64 is the carrier's base64 encoded PDF BOL/label, if requested
+	//and returned. Use WriteDocument to decode and save it.
+	documentBase64 string
+}
+
+//WriteDocument decodes the BOL/label PDF returned alongside a successful
+//submission and writes it to w. Returns an error if no document was returned.
+func (r *BOLResponse) WriteDocument(w io.Writer) error {
+	if r.documentBase64 == "" {
+		return errors.New("odfl.WriteDocument - no BOL document was returned for this response")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(r.documentBase64)
+	if err != nil {
+		return errors.Wrap(err, "odfl.WriteDocument - could not decode document")
+	}
+
+	_, err = w.Write(decoded)
+	if err != nil {
+		return errors.Wrap(err, "odfl.WriteDocument - could not write document")
+	}
+
+	return nil
+}
+
+//bolResponseWire is used to unmarshal a BOL response, including the document,
+//before it is copied into the public BOLResponse.
+type bolResponseWire struct {
+	ProNumber      string        `json:"proNumber" xml:"proNumber"`
+	Errors         []PickupError `json:"errors" xml:"errors>error"`
+	DocumentBase64 string        `json:"document" xml:"document"`
+}
+
+func (w *bolResponseWire) toBOLResponse() *BOLResponse {
+	return &BOLResponse{
+		ProNumber:      w.ProNumber,
+		Errors:         w.Errors,
+		documentBase64: w.DocumentBase64,
+	}
+}
+
+//resolveBOLURL returns the endpoint BOL requests should be posted to:
+//c.BOLURL if set, otherwise BOLURLTest/BOLURLProd (by TestMode) for
+//TransportSOAP. REST has no default - ODFL hasn't published one - so it's
+//an error to leave BOLURL unset with TransportREST.
+func (c *Client) resolveBOLURL() (string, error) {
+	if c.BOLURL != "" {
+		return c.BOLURL, nil
+	}
+	if c.Transport == TransportSOAP {
+		if c.TestMode {
+			return BOLURLTest, nil
+		}
+		return BOLURLProd, nil
+	}
+	return "", errors.New("odfl: BOLURL must be set (via WithBOLURL) when using TransportREST")
+}
+
+//SubmitBOL generates and submits a bill of lading using whichever transport
+//the Client was configured with.
+func (c *Client) SubmitBOL(b *BOL) (response *BOLResponse, err error) {
+	err = b.Validate()
+	if err != nil {
+		err = errors.Wrap(err, "odfl.SubmitBOL - bol failed validation")
+		return
+	}
+
+	url, err := c.resolveBOLURL()
+	if err != nil {
+		return
+	}
+
+	if c.Transport == TransportSOAP {
+		return c.submitBOLSOAP(b, url)
+	}
+
+	return c.submitBOLREST(b, url)
+}
+
+func (c *Client) submitBOLSOAP(b *BOL, url string) (response *BOLResponse, err error) {
+	b.SoapenvAttr = soapenv
+	b.BolAttr = bol
+
+	xmlBytes, err := xml.Marshal(b)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLSOAP - could not marshal xml")
+		return
+	}
+
+	res, err := c.HTTPClient.Post(url, "text/xml", bytes.NewReader(xmlBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLSOAP - could not make post request")
+		return
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLSOAP - could not read response")
+		return
+	}
+
+	envelope := struct {
+		XMLName xml.Name `xml:"Envelope"`
+		Body    struct {
+			BOLResponse bolResponseWire `xml:"bolResponse"`
+		} `xml:"Body"`
+	}{}
+	err = xml.Unmarshal(resBody, &envelope)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLSOAP - could not unmarshal response")
+		return
+	}
+
+	response = envelope.Body.BOLResponse.toBOLResponse()
+	return
+}
+
+//bolRequestJSON mirrors BOL's fields with json tags, since BOL's struct tags
+//are xml paths into the SOAP envelope.
+type bolRequestJSON struct {
+	ShipDate            string               `json:"shipDate"`
+	Shipper             BOLParty             `json:"shipper"`
+	Consignee           BOLParty             `json:"consignee"`
+	PaymentMethod       PaymentMethod        `json:"paymentMethod"`
+	Items               []BOLItem            `json:"items"`
+	BillTo              *BOLParty            `json:"billTo,omitempty"`
+	ReferenceNumbers    []BOLReferenceNumber `json:"referenceNumbers,omitempty"`
+	SpecialInstructions string               `json:"specialInstructions,omitempty"`
+	Accessorials        BOLAccessorials      `json:"accessorials"`
+	CODAmount           float64              `json:"codAmount,omitempty"`
+	CODPaymentMethod    string               `json:"codPaymentMethod,omitempty"`
+}
+
+func (c *Client) submitBOLREST(b *BOL, url string) (response *BOLResponse, err error) {
+	jsonBytes, err := json.Marshal(bolRequestJSON{
+		ShipDate:            b.ShipDate.WireString(),
+		Shipper:             b.Shipper,
+		Consignee:           b.Consignee,
+		PaymentMethod:       b.PaymentMethod,
+		Items:               b.Items,
+		BillTo:              b.BillTo,
+		ReferenceNumbers:    b.ReferenceNumbers,
+		SpecialInstructions: b.SpecialInstructions,
+		Accessorials:        b.Accessorials,
+		CODAmount:           b.CODAmount,
+		CODPaymentMethod:    b.CODPaymentMethod,
+	})
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLREST - could not marshal json")
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLREST - could not build request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.Credentials.Username, c.Credentials.Password)
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLREST - could not make post request")
+		return
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLREST - could not read response")
+		return
+	}
+
+	wire := bolResponseWire{}
+	err = json.Unmarshal(resBody, &wire)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.submitBOLREST - could not unmarshal response")
+		return
+	}
+
+	response = wire.toBOLResponse()
+	return
+}
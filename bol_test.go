@@ -0,0 +1,153 @@
+package odfl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func validBOL() *BOL {
+	return &BOL{
+		PaymentMethod: PaymentPrepaid,
+		Items: []BOLItem{
+			{HandlingUnits: 1, UnitType: "SKID", Pieces: 1, Weight: 100, FreightClass: "70", Description: "widgets"},
+		},
+	}
+}
+
+func TestBOLValidate(t *testing.T) {
+	if err := validBOL().Validate(); err != nil {
+		t.Fatalf("Validate() on a valid BOL returned an error: %v", err)
+	}
+
+	t.Run("missing payment method", func(t *testing.T) {
+		b := validBOL()
+		b.PaymentMethod = ""
+		if err := b.Validate(); err == nil {
+			t.Error("Validate() expected an error for a missing paymentMethod, got nil")
+		}
+	})
+
+	t.Run("third party without billTo", func(t *testing.T) {
+		b := validBOL()
+		b.PaymentMethod = PaymentThirdParty
+		if err := b.Validate(); err == nil {
+			t.Error("Validate() expected an error for third-party payment without billTo, got nil")
+		}
+	})
+
+	t.Run("third party with incomplete billTo", func(t *testing.T) {
+		b := validBOL()
+		b.PaymentMethod = PaymentThirdParty
+		b.BillTo = &BOLParty{CompanyName: "Acme"}
+		if err := b.Validate(); err == nil {
+			t.Error("Validate() expected an error for an incomplete billTo, got nil")
+		}
+	})
+
+	t.Run("cod amount without payment method", func(t *testing.T) {
+		b := validBOL()
+		b.CODAmount = 100
+		if err := b.Validate(); err == nil {
+			t.Error("Validate() expected an error for a codAmount without codPaymentMethod, got nil")
+		}
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		b := validBOL()
+		b.Items = nil
+		if err := b.Validate(); err == nil {
+			t.Error("Validate() expected an error for a BOL with no items, got nil")
+		}
+	})
+
+	t.Run("hazmat item missing required fields", func(t *testing.T) {
+		b := validBOL()
+		b.Items[0].Hazmat = true
+		if err := b.Validate(); err == nil {
+			t.Error("Validate() expected an error for a hazmat item missing UN number/packing group/technical name, got nil")
+		}
+	})
+
+	t.Run("hazmat item with required fields", func(t *testing.T) {
+		b := validBOL()
+		b.Items[0].Hazmat = true
+		b.Items[0].HazmatUNNumber = "UN1993"
+		b.Items[0].HazmatPackingGroup = "II"
+		b.Items[0].HazmatTechnicalName = "Flammable liquid"
+		if err := b.Validate(); err != nil {
+			t.Errorf("Validate() on a complete hazmat item returned an error: %v", err)
+		}
+	})
+}
+
+func TestBOLShipDateWireFormat(t *testing.T) {
+	b := validBOL()
+
+	date, err := ParseDate("2024-03-05")
+	if err != nil {
+		t.Fatalf("ParseDate returned an error: %v", err)
+	}
+	b.ShipDate = date
+
+	if got, want := b.ShipDate.WireString(), "20240305"; got != want {
+		t.Errorf("ShipDate.WireString() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveBOLURL(t *testing.T) {
+	t.Run("defaults to BOLURLTest in test mode", func(t *testing.T) {
+		c := NewClient()
+		url, err := c.resolveBOLURL()
+		if err != nil {
+			t.Fatalf("resolveBOLURL returned an error: %v", err)
+		}
+		if url != BOLURLTest {
+			t.Errorf("resolveBOLURL() = %q, want %q", url, BOLURLTest)
+		}
+	})
+
+	t.Run("defaults to BOLURLProd outside test mode", func(t *testing.T) {
+		c := NewClient(WithTestMode(false))
+		url, err := c.resolveBOLURL()
+		if err != nil {
+			t.Fatalf("resolveBOLURL returned an error: %v", err)
+		}
+		if url != BOLURLProd {
+			t.Errorf("resolveBOLURL() = %q, want %q", url, BOLURLProd)
+		}
+	})
+
+	t.Run("REST requires an explicit BOLURL", func(t *testing.T) {
+		c := NewClient(WithTransport(TransportREST))
+		if _, err := c.resolveBOLURL(); err == nil {
+			t.Error("resolveBOLURL() expected an error for TransportREST with no BOLURL, got nil")
+		}
+	})
+}
+
+func TestSubmitBOLREST(t *testing.T) {
+	var gotAuth bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, gotAuth = r.BasicAuth()
+		w.Write([]byte(`{"proNumber":"PRO456"}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(
+		WithTransport(TransportREST),
+		WithBOLURL(ts.URL),
+		WithCredentials(Credentials{Username: "user", Password: "pass"}),
+	)
+
+	response, err := c.SubmitBOL(validBOL())
+	if err != nil {
+		t.Fatalf("SubmitBOL returned an error: %v", err)
+	}
+	if !gotAuth {
+		t.Error("SubmitBOL did not send basic auth credentials")
+	}
+	if response.ProNumber != "PRO456" {
+		t.Errorf("ProNumber = %q, want %q", response.ProNumber, "PRO456")
+	}
+}
@@ -0,0 +1,468 @@
+package odfl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//Transport picks which wire format a Client uses to talk to the carrier.
+//ODFL is migrating its pickup/rate/trace endpoints from SOAP-XML to REST-JSON,
+//so callers can choose whichever their account has been migrated to.
+type Transport int
+
+const (
+	//TransportSOAP posts an xml SOAP envelope, same as the original API.
+	//Deprecated: use TransportREST once your account has been migrated.
+	TransportSOAP Transport = iota
+
+	//TransportREST posts json with basic auth against the new REST endpoints.
+	TransportREST
+)
+
+//Credentials holds the username/password used to authenticate against the
+//REST endpoints. SOAP requests still carry the odfl4meUser/odfl4mePassword
+//fields directly on Shipper.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+//Client holds everything needed to make pickup/rate-quote/trace/BOL requests
+//against either the legacy SOAP endpoints or the newer REST endpoints. A
+//Client is safe for concurrent use by multiple goroutines, including against
+//different accounts/environments - unlike the deprecated package-level Set*
+//functions, the only state a Client shares (its profile cache and SOAP
+//deprecation warning flag) is synchronized.
+type Client struct {
+	HTTPClient *http.Client
+
+	//PickupURL, RateQuoteURL, TraceURL, and BOLURL override the endpoint a
+	//Client posts that operation's requests to. Each is a separate carrier
+	//endpoint, so a Client used for more than one operation can't share a
+	//single URL. Leave unset to use ODFL's default SOAP endpoint for that
+	//operation (chosen using TestMode where the carrier publishes separate
+	//test/production URLs) - these must be set when Transport is
+	//TransportREST, since ODFL hasn't published default REST endpoints.
+	PickupURL    string
+	RateQuoteURL string
+	TraceURL     string
+	BOLURL       string
+
+	Timeout     time.Duration
+	TestMode    bool
+	Credentials Credentials
+	Transport   Transport
+
+	//ProfileTTL is how long a Profile fetched by (*Client).Profile is cached
+	//before it's fetched again. Defaults to 24 hours.
+	ProfileTTL time.Duration
+
+	//ProfileSource overrides how (*Client).Profile loads a Profile. Leave nil
+	//to use the bundled profile_default.json - set this to fetch from a
+	//carrier endpoint if ODFL ever exposes one, or to load your own updated
+	//copy of the file without a code release.
+	ProfileSource func(ctx context.Context) (*Profile, error)
+
+	//profileCache holds the last Profile (*Client).Profile fetched. It's a
+	//pointer so copies of this Client made by the deprecated Set* shims
+	//(see defaultClient) share one cache instead of each re-fetching.
+	profileCache *profileCache
+
+	//soapDeprecationWarned tracks whether the TransportSOAP deprecation
+	//warning has already been logged. It's a pointer, like profileCache, so
+	//copies of this Client made by the deprecated Set* shims share one flag
+	//instead of each logging the warning again on every request.
+	soapDeprecationWarned *sync.Once
+}
+
+//Option configures a Client built with NewClient.
+type Option func(*Client)
+
+//WithPickupURL overrides the endpoint a Client posts pickup requests to.
+func WithPickupURL(url string) Option {
+	return func(c *Client) {
+		c.PickupURL = url
+	}
+}
+
+//WithRateQuoteURL overrides the endpoint a Client posts rate quote requests to.
+func WithRateQuoteURL(url string) Option {
+	return func(c *Client) {
+		c.RateQuoteURL = url
+	}
+}
+
+//WithTraceURL overrides the endpoint a Client posts trace requests to.
+func WithTraceURL(url string) Option {
+	return func(c *Client) {
+		c.TraceURL = url
+	}
+}
+
+//WithBOLURL overrides the endpoint a Client posts BOL requests to.
+func WithBOLURL(url string) Option {
+	return func(c *Client) {
+		c.BOLURL = url
+	}
+}
+
+//WithCredentials sets the username/password a Client authenticates REST
+//requests with.
+func WithCredentials(credentials Credentials) Option {
+	return func(c *Client) {
+		c.Credentials = credentials
+	}
+}
+
+//WithTransport picks SOAP or REST for a Client.
+func WithTransport(transport Transport) Option {
+	return func(c *Client) {
+		c.Transport = transport
+	}
+}
+
+//WithTestMode sets whether a Client schedules real pickups (false) or only
+//test ones (true, the default).
+func WithTestMode(testMode bool) Option {
+	return func(c *Client) {
+		c.TestMode = testMode
+	}
+}
+
+//WithTimeout sets how long a Client waits for a reply before giving up.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.Timeout = d
+		c.HTTPClient.Timeout = d
+	}
+}
+
+//WithHTTPClient overrides the *http.Client a Client uses to make requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+//WithProfileTTL sets how long a Client caches the Profile it fetches.
+func WithProfileTTL(d time.Duration) Option {
+	return func(c *Client) {
+		c.ProfileTTL = d
+	}
+}
+
+//WithProfileSource overrides how a Client loads its Profile, instead of
+//parsing the bundled profile_default.json. Use this to point at a carrier
+//capabilities endpoint, or to load an updated profile file of your own.
+func WithProfileSource(source func(ctx context.Context) (*Profile, error)) Option {
+	return func(c *Client) {
+		c.ProfileSource = source
+	}
+}
+
+//PickupURLDefault is the SOAP endpoint pickup requests are posted to when a
+//Client's PickupURL is unset. ODFL doesn't publish a separate test/production
+//pickup URL - test pickups are distinguished by the testFlag field instead.
+var PickupURLDefault = "http://www.odfl.com/wsPickup_v1b/services/ODPickupSOAP"
+
+//NewClient builds a Client, defaulting to the legacy SOAP transport in test
+//mode with a 10 second timeout. Pass Options to override any of these.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		HTTPClient:            &http.Client{Timeout: 10 * time.Second},
+		Timeout:               10 * time.Second,
+		TestMode:              true,
+		Transport:             TransportSOAP,
+		ProfileTTL:            24 * time.Hour,
+		profileCache:          &profileCache{},
+		soapDeprecationWarned: &sync.Once{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+//resolvePickupURL returns the endpoint pickup requests should be posted to:
+//c.PickupURL if set, otherwise PickupURLDefault for TransportSOAP. REST has
+//no default - ODFL hasn't published one - so it's an error to leave
+//PickupURL unset with TransportREST.
+func (c *Client) resolvePickupURL() (string, error) {
+	if c.PickupURL != "" {
+		return c.PickupURL, nil
+	}
+	if c.Transport == TransportSOAP {
+		return PickupURLDefault, nil
+	}
+	return "", errors.New("odfl: PickupURL must be set (via WithPickupURL) when using TransportREST")
+}
+
+//defaultClient backs the deprecated package-level SetProductionMode/
+//SetTimeout/RequestPickup shims. It's stored in an atomic.Value so readers
+//never need to lock, and Set* calls swap in a new Client rather than
+//mutating one in place, so a pickup already in flight always sees a
+//consistent snapshot of the settings.
+var defaultClient atomic.Value
+
+func init() {
+	defaultClient.Store(NewClient())
+}
+
+//getDefaultClient returns the Client backing the deprecated package-level
+//Set*/RequestPickup shims.
+func getDefaultClient() *Client {
+	return defaultClient.Load().(*Client)
+}
+
+//PickupResponse is the parsed result of a pickup request, for either transport.
+type PickupResponse struct {
+	ConfirmationNumber string        `json:"confirmationNumber" xml:"confirmationNumber"`
+	PickupNumber       string        `json:"pickupNumber" xml:"pickupNumber"`
+	PickupDate         string        `json:"pickupDate" xml:"pickupDate"`
+	Errors             []PickupError `json:"errors" xml:"errors>error"`
+}
+
+//PickupError is one error returned alongside (or instead of) a pickup confirmation.
+type PickupError struct {
+	Code    string `json:"code" xml:"code"`
+	Message string `json:"message" xml:"message"`
+}
+
+//RequestPickup schedules a pickup using whichever transport the Client was
+//configured with.
+func (c *Client) RequestPickup(p *PickupRequest) (response *PickupResponse, err error) {
+	url, err := c.resolvePickupURL()
+	if err != nil {
+		return
+	}
+
+	if c.Transport == TransportSOAP {
+		c.soapDeprecationWarned.Do(func() {
+			log.Println("odfl: Deprecated - TransportSOAP is deprecated, switch to TransportREST once your account supports it")
+		})
+		return c.requestPickupSOAP(p, url)
+	}
+
+	return c.requestPickupREST(p, url)
+}
+
+//requestPickupSOAP posts the same xml SOAP envelope used by the package-level
+//RequestPickup, against url.
+func (c *Client) requestPickupSOAP(p *PickupRequest, url string) (response *PickupResponse, err error) {
+	p.SoapenvAttr = soapenv
+	p.PicAttr = pic
+	p.Shipper.TestFlag = c.TestMode
+	p.Shipper.deriveAMPM()
+
+	xmlBytes, err := xml.Marshal(p)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupSOAP - could not marshal xml")
+		return
+	}
+
+	res, err := c.HTTPClient.Post(url, "text/xml", bytes.NewReader(xmlBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupSOAP - could not make post request")
+		return
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupSOAP - could not read response")
+		return
+	}
+
+	envelope := struct {
+		XMLName xml.Name `xml:"Envelope"`
+		Body    struct {
+			PickupResponse PickupResponse `xml:"pickupResponse"`
+		} `xml:"Body"`
+	}{}
+	err = xml.Unmarshal(body, &envelope)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupSOAP - could not unmarshal response")
+		return
+	}
+
+	response = &envelope.Body.PickupResponse
+	return
+}
+
+//pickupRequestJSON mirrors PickupRequest's shipper/consignee fields with json
+//tags, since PickupRequest's struct tags are xml paths into the SOAP envelope.
+type pickupRequestJSON struct {
+	Shipper   shipperJSON   `json:"shipper"`
+	Consignee consigneeJSON `json:"consignee"`
+}
+
+type shipperJSON struct {
+	ODFL4MeUser     string `json:"odfl4meUser,omitempty"`
+	ODFL4MePassword string `json:"odfl4mePassword,omitempty"`
+	CompanyName     string `json:"companyName"`
+	AddressLine1    string `json:"addressLine1"`
+	AddressLine2    string `json:"addressLine2,omitempty"`
+	City            string `json:"city"`
+	StateProvince   string `json:"stateProvince"`
+	PostalCode      string `json:"postalCode"`
+	Country         string `json:"country"`
+	ContactFName    string `json:"contactFName"`
+	ContactLName    string `json:"contactLName"`
+	PhoneAreaCode   string `json:"phoneAreaCode"`
+	PhoneNumber     string `json:"phoneNumber"`
+	PhoneExt        string `json:"phoneExt,omitempty"`
+	FaxAreaCode     string `json:"faxAreaCode,omitempty"`
+	FaxNumber       string `json:"faxNumber,omitempty"`
+	Email           string `json:"email,omitempty"`
+	Attention       string `json:"attention,omitempty"`
+	Comments        string `json:"comments,omitempty"`
+	AccountNumber   string `json:"accountNumber,omitempty"`
+	TestFlag        bool   `json:"testFlag"`
+	PickupDate      string `json:"pickupDate"`
+	PickupTime      string `json:"pickupTime"`
+	PickupTimeAMPM  string `json:"pickupTimeAMPM"`
+	DockCloseTime   string `json:"dockCloseTime,omitempty"`
+	DockCloseAMPM   string `json:"dockCloseAMPM,omitempty"`
+	WhoEntered      string `json:"whoEntered"`
+	WhoPhoneNumber  string `json:"whoPhoneNumber"`
+}
+
+type consigneeJSON struct {
+	CustomerShipmentID string  `json:"customerShipmentId"`
+	CompanyName        string  `json:"companyName,omitempty"`
+	AddressLine1       string  `json:"addressLine1,omitempty"`
+	AddressLine2       string  `json:"addressLine2,omitempty"`
+	City               string  `json:"city"`
+	StateProvince      string  `json:"stateProvince"`
+	PostalCode         string  `json:"postalCode"`
+	Country            string  `json:"country"`
+	ContactFName       string  `json:"contactFName,omitempty"`
+	ContactLName       string  `json:"contactLName,omitempty"`
+	PhoneAreaCode      string  `json:"phoneAreaCode"`
+	PhoneNumber        string  `json:"phoneNumber"`
+	PhoneExt           string  `json:"phoneExt,omitempty"`
+	FaxAreaCode        string  `json:"faxAreaCode,omitempty"`
+	FaxNumber          string  `json:"faxNumber,omitempty"`
+	Email              string  `json:"email,omitempty"`
+	PaymentMethod      string  `json:"paymentMethod,omitempty"`
+	HandlingUnits      uint    `json:"handlingUnits"`
+	Pieces             uint    `json:"pieces"`
+	UnitType           string  `json:"unitType"`
+	Weight             float64 `json:"weight"`
+	Hazmat             string  `json:"hazmat,omitempty"`
+	Freezable          string  `json:"freezable,omitempty"`
+	Description        string  `json:"description,omitempty"`
+}
+
+//requestPickupREST marshals p to json and posts it to url with basic auth,
+//unmarshaling the result into a PickupResponse.
+func (c *Client) requestPickupREST(p *PickupRequest, url string) (response *PickupResponse, err error) {
+	p.Shipper.deriveAMPM()
+
+	body := pickupRequestJSON{
+		Shipper: shipperJSON{
+			ODFL4MeUser:     p.Shipper.ODFL4MeUser,
+			ODFL4MePassword: p.Shipper.ODFL4MePassword,
+			CompanyName:     p.Shipper.CompanyName,
+			AddressLine1:    p.Shipper.AddressLine1,
+			AddressLine2:    p.Shipper.AddressLine2,
+			City:            p.Shipper.City,
+			StateProvince:   p.Shipper.StateProvince,
+			PostalCode:      p.Shipper.PostalCode,
+			Country:         p.Shipper.Country,
+			ContactFName:    p.Shipper.ContactFName,
+			ContactLName:    p.Shipper.ContactLName,
+			PhoneAreaCode:   p.Shipper.PhoneAreaCode,
+			PhoneNumber:     p.Shipper.PhoneNumber,
+			PhoneExt:        p.Shipper.PhoneExt,
+			FaxAreaCode:     p.Shipper.FaxAreaCode,
+			FaxNumber:       p.Shipper.FaxNumber,
+			Email:           p.Shipper.Email,
+			Attention:       p.Shipper.Attention,
+			Comments:        p.Shipper.Comments,
+			AccountNumber:   p.Shipper.AccountNumber,
+			TestFlag:        c.TestMode,
+			PickupDate:      p.Shipper.PickupDate.WireString(),
+			PickupTime:      p.Shipper.PickupTime.WireString(),
+			PickupTimeAMPM:  p.Shipper.PickupTimeAMPM,
+			DockCloseTime:   p.Shipper.DockCloseTime.WireString(),
+			DockCloseAMPM:   p.Shipper.DockCloseAMPM,
+			WhoEntered:      p.Shipper.WhoEntered,
+			WhoPhoneNumber:  p.Shipper.WhoPhoneNumber,
+		},
+		Consignee: consigneeJSON{
+			CustomerShipmentID: p.Consignee.CustomerShipmentID,
+			CompanyName:        p.Consignee.CompanyName,
+			AddressLine1:       p.Consignee.AddressLine1,
+			AddressLine2:       p.Consignee.AddressLine2,
+			City:               p.Consignee.City,
+			StateProvince:      p.Consignee.StateProvince,
+			PostalCode:         p.Consignee.PostalCode,
+			Country:            p.Consignee.Country,
+			ContactFName:       p.Consignee.ContactFName,
+			ContactLName:       p.Consignee.ContactLName,
+			PhoneAreaCode:      p.Consignee.PhoneAreaCode,
+			PhoneNumber:        p.Consignee.PhoneNumber,
+			PhoneExt:           p.Consignee.PhoneExt,
+			FaxAreaCode:        p.Consignee.FaxAreaCode,
+			FaxNumber:          p.Consignee.FaxNumber,
+			Email:              p.Consignee.Email,
+			PaymentMethod:      p.Consignee.PaymentMethod,
+			HandlingUnits:      p.Consignee.HandlingUnits,
+			Pieces:             p.Consignee.Pieces,
+			UnitType:           p.Consignee.UnitType,
+			Weight:             p.Consignee.Weight,
+			Hazmat:             p.Consignee.Hazmat,
+			Freezable:          p.Consignee.Freezable,
+			Description:        p.Consignee.Description,
+		},
+	}
+
+	jsonBytes, err := json.Marshal(body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupREST - could not marshal json")
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupREST - could not build request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.Credentials.Username, c.Credentials.Password)
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupREST - could not make post request")
+		return
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupREST - could not read response")
+		return
+	}
+
+	response = &PickupResponse{}
+	err = json.Unmarshal(resBody, response)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.requestPickupREST - could not unmarshal response")
+		return
+	}
+
+	return
+}
@@ -0,0 +1,108 @@
+package odfl
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestResolvePickupURL(t *testing.T) {
+	t.Run("defaults to PickupURLDefault under SOAP", func(t *testing.T) {
+		c := NewClient()
+		url, err := c.resolvePickupURL()
+		if err != nil {
+			t.Fatalf("resolvePickupURL returned an error: %v", err)
+		}
+		if url != PickupURLDefault {
+			t.Errorf("resolvePickupURL() = %q, want %q", url, PickupURLDefault)
+		}
+	})
+
+	t.Run("explicit PickupURL wins", func(t *testing.T) {
+		c := NewClient(WithPickupURL("http://example.com/pickup"))
+		url, err := c.resolvePickupURL()
+		if err != nil {
+			t.Fatalf("resolvePickupURL returned an error: %v", err)
+		}
+		if url != "http://example.com/pickup" {
+			t.Errorf("resolvePickupURL() = %q, want the explicit PickupURL", url)
+		}
+	})
+
+	t.Run("REST requires an explicit PickupURL", func(t *testing.T) {
+		c := NewClient(WithTransport(TransportREST))
+		if _, err := c.resolvePickupURL(); err == nil {
+			t.Error("resolvePickupURL() expected an error for TransportREST with no PickupURL, got nil")
+		}
+	})
+}
+
+func TestRequestPickupSOAP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<Envelope><Body><pickupResponse><confirmationNumber>abc123</confirmationNumber></pickupResponse></Body></Envelope>`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(WithPickupURL(ts.URL))
+
+	res, err := c.RequestPickup(&PickupRequest{})
+	if err != nil {
+		t.Fatalf("RequestPickup returned an error: %v", err)
+	}
+	if res.ConfirmationNumber != "abc123" {
+		t.Errorf("ConfirmationNumber = %q, want %q", res.ConfirmationNumber, "abc123")
+	}
+}
+
+func TestRequestPickupREST(t *testing.T) {
+	var gotAuth bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, gotAuth = r.BasicAuth()
+		w.Write([]byte(`{"confirmationNumber":"xyz789"}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(
+		WithTransport(TransportREST),
+		WithPickupURL(ts.URL),
+		WithCredentials(Credentials{Username: "user", Password: "pass"}),
+	)
+
+	res, err := c.RequestPickup(&PickupRequest{})
+	if err != nil {
+		t.Fatalf("RequestPickup returned an error: %v", err)
+	}
+	if !gotAuth {
+		t.Error("RequestPickup did not send basic auth credentials")
+	}
+	if res.ConfirmationNumber != "xyz789" {
+		t.Errorf("ConfirmationNumber = %q, want %q", res.ConfirmationNumber, "xyz789")
+	}
+}
+
+func TestRequestPickupSOAPDeprecationWarningOnce(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<Envelope><Body><pickupResponse></pickupResponse></Body></Envelope>`))
+	}))
+	defer ts.Close()
+
+	var logOutput bytes.Buffer
+	log.SetOutput(&logOutput)
+	defer log.SetOutput(os.Stderr)
+
+	c := NewClient(WithPickupURL(ts.URL))
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.RequestPickup(&PickupRequest{}); err != nil {
+			t.Fatalf("RequestPickup returned an error: %v", err)
+		}
+	}
+
+	if got, want := strings.Count(logOutput.String(), "Deprecated"), 1; got != want {
+		t.Errorf("deprecation warning logged %d times across 3 requests, want %d", got, want)
+	}
+}
@@ -0,0 +1,178 @@
+package odfl
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//dateLayout is the yyyymmdd format ODFL requires on the wire for date fields
+const dateLayout = "20060102"
+
+//timeOfDayLayout is the hhmmss (24-hour) format ODFL requires on the wire
+//for time-of-day fields
+const timeOfDayLayout = "150405"
+
+//dateLayouts are the formats accepted when parsing a Date, tried in order.
+//This lets callers feed in dates from ERPs/spreadsheets/etc. without having
+//to convert to the carrier's native yyyymmdd format themselves.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02",
+	dateLayout,
+	"1/2/2006",
+}
+
+//timeOfDayLayouts are the formats accepted when parsing a TimeOfDay, tried
+//in order.
+var timeOfDayLayouts = []string{
+	time.RFC3339,
+	"15:04:05",
+	"15:04",
+	"3:04 PM",
+	"3:04PM",
+	timeOfDayLayout,
+}
+
+//Date wraps time.Time so PickupRequest date fields (PickupDate, etc.) can be
+//set from whatever format a caller already has on hand, while always
+//emitting the carrier's required yyyymmdd format on the wire.
+type Date struct {
+	time.Time
+}
+
+//NewDate wraps t as a Date.
+func NewDate(t time.Time) Date {
+	return Date{Time: t}
+}
+
+//ParseDate parses value using whichever of Date's accepted formats matches
+//(RFC3339, "2006-01-02", "20060102", "1/2/2006").
+func ParseDate(value string) (Date, error) {
+	t, err := parseWithLayouts(value, dateLayouts)
+	if err != nil {
+		return Date{}, errors.Wrap(err, "odfl: could not parse date")
+	}
+
+	return Date{Time: t}, nil
+}
+
+//WireString formats d in the carrier's required yyyymmdd format, or returns
+//"" if d is the zero value, so an unset Date is never mistaken for the
+//literal date 0001-01-01.
+func (d Date) WireString() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return d.Format(dateLayout)
+}
+
+//MarshalXML emits the date in the carrier's required yyyymmdd format, or an
+//empty element if d is the zero value.
+func (d Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(d.WireString(), start)
+}
+
+//UnmarshalXML accepts any of Date's supported formats.
+func (d *Date) UnmarshalXML(de *xml.Decoder, start xml.StartElement) error {
+	var value string
+	if err := de.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+
+	t, err := parseWithLayouts(value, dateLayouts)
+	if err != nil {
+		return errors.Wrapf(err, "odfl: could not parse %q as %s", value, start.Name.Local)
+	}
+
+	d.Time = t
+	return nil
+}
+
+//TimeOfDay wraps time.Time so PickupRequest time fields (PickupTime,
+//DockCloseTime) can be set from whatever format a caller already has on
+//hand, while always emitting the carrier's required hhmmss format on the
+//wire. Only the hour/minute/second of the wrapped time are meaningful.
+type TimeOfDay struct {
+	time.Time
+}
+
+//NewTimeOfDay wraps t as a TimeOfDay.
+func NewTimeOfDay(t time.Time) TimeOfDay {
+	return TimeOfDay{Time: t}
+}
+
+//ParseTimeOfDay parses value using whichever of TimeOfDay's accepted
+//formats matches ("15:04", "15:04:05", "3:04 PM", "hhmmss", RFC3339).
+func ParseTimeOfDay(value string) (TimeOfDay, error) {
+	t, err := parseWithLayouts(value, timeOfDayLayouts)
+	if err != nil {
+		return TimeOfDay{}, errors.Wrap(err, "odfl: could not parse time")
+	}
+
+	return TimeOfDay{Time: t}, nil
+}
+
+//WireString formats t in the carrier's required hhmmss format, or returns
+//"" if t is the zero value, so an unset TimeOfDay is never mistaken for the
+//literal time 00:00:00.
+func (t TimeOfDay) WireString() string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(timeOfDayLayout)
+}
+
+//MarshalXML emits the time in the carrier's required hhmmss format, or an
+//empty element if t is the zero value.
+func (t TimeOfDay) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(t.WireString(), start)
+}
+
+//UnmarshalXML accepts any of TimeOfDay's supported formats.
+func (t *TimeOfDay) UnmarshalXML(de *xml.Decoder, start xml.StartElement) error {
+	var value string
+	if err := de.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+
+	parsed, err := parseWithLayouts(value, timeOfDayLayouts)
+	if err != nil {
+		return errors.Wrapf(err, "odfl: could not parse %q as %s", value, start.Name.Local)
+	}
+
+	t.Time = parsed
+	return nil
+}
+
+//AMPM returns "AM" or "PM" matching the wrapped time, for carrier fields
+//like pickupTimeAMPM/dockCloseAMPM that must be sent alongside the 24-hour
+//time value.
+func (t TimeOfDay) AMPM() string {
+	if t.Hour() < 12 {
+		return "AM"
+	}
+
+	return "PM"
+}
+
+//parseWithLayouts tries each layout in order, returning the first match.
+func parseWithLayouts(value string, layouts []string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	var lastErr error
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+
+		lastErr = err
+	}
+
+	return time.Time{}, errors.Wrapf(lastErr, "could not parse %q with any known format", value)
+}
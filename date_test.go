@@ -0,0 +1,132 @@
+package odfl
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string //yyyymmdd
+	}{
+		{"rfc3339", "2024-03-05T00:00:00Z", "20240305"},
+		{"dash", "2024-03-05", "20240305"},
+		{"wire", "20240305", "20240305"},
+		{"slash", "3/5/2024", "20240305"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseDate(tt.value)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) returned error: %v", tt.value, err)
+			}
+			if got := d.WireString(); got != tt.want {
+				t.Errorf("ParseDate(%q).WireString() = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseDate("not a date"); err == nil {
+		t.Error("ParseDate(\"not a date\") expected an error, got nil")
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string //hhmmss
+	}{
+		{"24h", "15:04:05", "150405"},
+		{"24h-no-seconds", "15:04", "150400"},
+		{"12h-space", "3:04 PM", "150400"},
+		{"12h-nospace", "3:04PM", "150400"},
+		{"wire", "150405", "150405"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm, err := ParseTimeOfDay(tt.value)
+			if err != nil {
+				t.Fatalf("ParseTimeOfDay(%q) returned error: %v", tt.value, err)
+			}
+			if got := tm.WireString(); got != tt.want {
+				t.Errorf("ParseTimeOfDay(%q).WireString() = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseTimeOfDay("not a time"); err == nil {
+		t.Error("ParseTimeOfDay(\"not a time\") expected an error, got nil")
+	}
+}
+
+func TestTimeOfDayAMPM(t *testing.T) {
+	morning, _ := ParseTimeOfDay("09:00")
+	if got := morning.AMPM(); got != "AM" {
+		t.Errorf("AMPM() for 09:00 = %q, want AM", got)
+	}
+
+	afternoon, _ := ParseTimeOfDay("15:00")
+	if got := afternoon.AMPM(); got != "PM" {
+		t.Errorf("AMPM() for 15:00 = %q, want PM", got)
+	}
+}
+
+//TestDateMarshalXMLZeroValue guards against a zero Date being marshaled as
+//the literal date 0001-01-01 instead of an empty element.
+func TestDateMarshalXMLZeroValue(t *testing.T) {
+	type wrapper struct {
+		D Date `xml:"d"`
+	}
+
+	out, err := xml.Marshal(wrapper{})
+	if err != nil {
+		t.Fatalf("xml.Marshal returned error: %v", err)
+	}
+
+	if got, want := string(out), "<wrapper><d></d></wrapper>"; got != want {
+		t.Errorf("xml.Marshal(zero Date) = %q, want %q", got, want)
+	}
+
+	set := wrapper{D: NewDate(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))}
+	out, err = xml.Marshal(set)
+	if err != nil {
+		t.Fatalf("xml.Marshal returned error: %v", err)
+	}
+
+	if got, want := string(out), "<wrapper><d>20240305</d></wrapper>"; got != want {
+		t.Errorf("xml.Marshal(set Date) = %q, want %q", got, want)
+	}
+}
+
+//TestTimeOfDayMarshalXMLZeroValue guards against a zero TimeOfDay being
+//marshaled as the literal time 00:00:00 instead of an empty element.
+func TestTimeOfDayMarshalXMLZeroValue(t *testing.T) {
+	type wrapper struct {
+		T TimeOfDay `xml:"t"`
+	}
+
+	out, err := xml.Marshal(wrapper{})
+	if err != nil {
+		t.Fatalf("xml.Marshal returned error: %v", err)
+	}
+
+	if got, want := string(out), "<wrapper><t></t></wrapper>"; got != want {
+		t.Errorf("xml.Marshal(zero TimeOfDay) = %q, want %q", got, want)
+	}
+
+	set := wrapper{T: NewTimeOfDay(time.Date(2024, 3, 5, 13, 30, 0, 0, time.UTC))}
+	out, err = xml.Marshal(set)
+	if err != nil {
+		t.Fatalf("xml.Marshal returned error: %v", err)
+	}
+
+	if got, want := string(out), "<wrapper><t>133000</t></wrapper>"; got != want {
+		t.Errorf("xml.Marshal(set TimeOfDay) = %q, want %q", got, want)
+	}
+}
@@ -4,40 +4,87 @@ documentation.  This uses and XML SOAP API.
 
 Currently this package can perform:
 - pickup requests
+- rate quotes
+- shipment tracing
 
 To create a pickup request:
 - Set test or production mode (SetProductionMode()).
-- Set shipper information (Shipper{}).
+- Set shipper information (Shipper{}). PickupDate/PickupTime/DockCloseTime
+  accept a variety of common formats (RFC3339, "2006-01-02", "1/2/2006",
+  "15:04", "3:04 PM", etc.) via Date/TimeOfDay - see ParseDate/ParseTimeOfDay.
+  PickupTimeAMPM/DockCloseAMPM are derived automatically, don't set them.
 - Set shipment data (Consignee{}).
 - Create the pickup request object (PickupRequest{}).
 - Request the pickup (RequestPickup()).
 - Check for any errors.
+
+To get a rate quote:
+- Set test or production mode (SetProductionMode()).
+- Create the rate quote request object (RateQuoteRequest{}).
+- Request the quote (GetRateQuote()).
+- Check for any errors.
+
+To trace a shipment:
+- Set test or production mode (SetProductionMode()).
+- Create the trace request object (TraceRequest{}), keyed by ProNumber or
+  by BOLNumber and OriginPostalCode.
+- Request the trace (TraceShipment()).
+- Check for any errors.
+
+ODFL is migrating pickup/rate/trace off of SOAP-XML onto REST-JSON with basic
+auth. Pickups can now also be scheduled through a Client, which picks between
+the legacy SOAP endpoint (TransportSOAP, deprecated) and the new REST endpoint
+(TransportREST):
+- Build a Client with NewClient(), passing Options (WithCredentials,
+  WithTransport, WithTestMode, WithTimeout) to configure it.
+- Call (*Client).RequestPickup() with a PickupRequest.
+- Check the returned PickupResponse/error.
+
+Pickup, rate quote, trace, and BOL are four separate carrier endpoints, so a
+Client keeps a separate URL per operation (PickupURL, RateQuoteURL, TraceURL,
+BOLURL) instead of one shared BaseURL. Each defaults to ODFL's SOAP endpoint
+for that operation (picking test or production by TestMode, where ODFL
+publishes both) when Transport is TransportSOAP - set WithPickupURL/
+WithRateQuoteURL/WithTraceURL/WithBOLURL to override one, which is required
+under TransportREST since ODFL hasn't published default REST endpoints.
+
+A Client holds its own HTTPClient/TestMode/Timeout/Credentials, so multiple
+goroutines can safely issue pickups, rate quotes, and traces against
+different accounts or environments concurrently without racing on shared
+state. (*Client).GetRateQuote()/(*Client).TraceShipment() work the same way
+as (*Client).RequestPickup(). The package-level SetProductionMode/SetTimeout/
+RequestPickup/GetRateQuote/TraceShipment functions are thin backwards
+compatible shims around a default Client - prefer building your own Client
+for anything more than quick scripts.
+
+To generate and submit a bill of lading:
+- Build a Client, same as for pickups.
+- Build the BOL{}, including its line items (BOLItem{}) and, for third-party
+  billing, a BillTo party.
+- Call bol.Validate() to catch missing required fields (per payment method
+  and per hazmat item) before hitting the wire.
+- Call (*Client).SubmitBOL() to submit it and get back the PRO number.
+- If a BOL image/label was returned, save it with (*BOLResponse).WriteDocument().
+
+A Client also exposes (*Client).Profile(), a snapshot of which unit types,
+countries, accessorials, and hazmat lanes ODFL currently supports, plus its
+weight/dimension limits per handling unit. It's fetched once and cached for
+ProfileTTL (WithProfileTTL). ODFL has no first-class capabilities endpoint,
+so by default Profile() parses the bundled profile_default.json - set
+WithProfileSource() to load a carrier endpoint or an updated copy of that
+file instead, without a code release. Call (*PickupRequest).Validate() with
+a Client to check that a pickup's date/time were set, and its unit type,
+weight, country, hazmat/freezable accessorials, and hazmat lane are all
+supported, against the cached Profile, before sending it.
 */
 package odfl
 
 import (
-	"bytes"
 	"encoding/xml"
-	"io/ioutil"
-	"log"
 	"net/http"
 	"time"
-
-	"github.com/pkg/errors"
 )
 
-//odURL is the URL used to make API calls
-var odURL = "http://www.odfl.com/wsPickup_v1b/services/ODPickupSOAP"
-
-//timeout is the default time we should wait for a reply from Ward
-//You may need to adjust this based on how slow connecting to Ward is for you.
-//10 seconds is overly long, but sometimes Ward is very slow.
-var timeout = time.Duration(10 * time.Second)
-
-//testMode is used to make test or real pickup requests
-//Set to false by SetProductionMode() to schedule real pickups
-var testMode = true
-
 //base XML data
 var (
 	soapenv = "http://schemas.xmlsoap.org/soap/envelope/"
@@ -58,36 +105,48 @@ type PickupRequest struct {
 //Shipper is the data on the shipper
 type Shipper struct {
 	//required
-	ODFL4MeUser     string `xml:"odfl4meUser"` //web login
-	ODFL4MePassword string `xml:"odfl4mePassword"`
-	CompanyName     string `xml:"companyName"` //where shipment is coming from
-	AddressLine1    string `xml:"addressLine1"`
-	City            string `xml:"city"`
-	StateProvince   string `xml:"stateProvince"` //two characters
-	PostalCode      string `xml:"postalCode"`
-	Country         string `xmml:"country"` //USA, CAN, or MEX
-	ContactFName    string `xml:"contactFName"`
-	ContactLName    string `xml:"contactLName"`
-	PhoneAreaCode   string `xml:"phoneAreaCode"`  //first three of phone number, no + or +1
-	PhoneNumber     string `xml:"phoneNumber"`    //last 7 digits of phone number
-	TestFlag        bool   `xml:"testFlag"`       //set to true to NOT schedule a real pickup
-	PickupDate      string `xml:"pickupDate"`     //yyyymmdd
-	PickupTime      string `xml:"pickupTime"`     //hhmmss
-	PickupTimeAMPM  string `xml:"pickupTimeAMPM"` //AM or PM
-	WhoEntered      string `xml:"whoEntered"`     //who scheduled the pickup
-	WhoPhoneNumber  string `xml:"whoPhoneNumber"`
+	ODFL4MeUser     string    `xml:"odfl4meUser"` //web login
+	ODFL4MePassword string    `xml:"odfl4mePassword"`
+	CompanyName     string    `xml:"companyName"` //where shipment is coming from
+	AddressLine1    string    `xml:"addressLine1"`
+	City            string    `xml:"city"`
+	StateProvince   string    `xml:"stateProvince"` //two characters
+	PostalCode      string    `xml:"postalCode"`
+	Country         string    `xmml:"country"` //USA, CAN, or MEX
+	ContactFName    string    `xml:"contactFName"`
+	ContactLName    string    `xml:"contactLName"`
+	PhoneAreaCode   string    `xml:"phoneAreaCode"` //first three of phone number, no + or +1
+	PhoneNumber     string    `xml:"phoneNumber"`   //last 7 digits of phone number
+	TestFlag        bool      `xml:"testFlag"`      //set to true to NOT schedule a real pickup
+	PickupDate      Date      `xml:"pickupDate"`
+	PickupTime      TimeOfDay `xml:"pickupTime"`
+	PickupTimeAMPM  string    `xml:"pickupTimeAMPM"` //derived from PickupTime, do not set directly
+	WhoEntered      string    `xml:"whoEntered"`     //who scheduled the pickup
+	WhoPhoneNumber  string    `xml:"whoPhoneNumber"`
 
 	//optional
-	AccountNumber string `xml:"accountNumber"` //odfl account number
-	Attention     string `xml:"attention"`     //shipping dept or a person's name to contact with pickup issues
-	AddressLine2  string `xml:"addressLine2"`
-	PhoneExt      string `xml:"phoneExt"` //no "x" or non-numeric characters
-	FaxAreaCode   string `xml:"faxAreaCode"`
-	FaxNumber     string `xml:"faxNumber"`
-	Email         string `xml:"email"`
-	Comments      string `xml:"comments"`      //special instructions or special services
-	DockCloseTime string `xml:"dockCloseTime"` //hhmmss
-	DockCloseAMPM string `xml:"dockCloseAMPM"` //AM or PM
+	AccountNumber string    `xml:"accountNumber"` //odfl account number
+	Attention     string    `xml:"attention"`     //shipping dept or a person's name to contact with pickup issues
+	AddressLine2  string    `xml:"addressLine2"`
+	PhoneExt      string    `xml:"phoneExt"` //no "x" or non-numeric characters
+	FaxAreaCode   string    `xml:"faxAreaCode"`
+	FaxNumber     string    `xml:"faxNumber"`
+	Email         string    `xml:"email"`
+	Comments      string    `xml:"comments"` //special instructions or special services
+	DockCloseTime TimeOfDay `xml:"dockCloseTime"`
+	DockCloseAMPM string    `xml:"dockCloseAMPM"` //derived from DockCloseTime, do not set directly
+}
+
+//deriveAMPM fills in PickupTimeAMPM/DockCloseAMPM from PickupTime/
+//DockCloseTime, so callers don't have to split hour and meridiem manually.
+func (s *Shipper) deriveAMPM() {
+	if !s.PickupTime.IsZero() {
+		s.PickupTimeAMPM = s.PickupTime.AMPM()
+	}
+
+	if !s.DockCloseTime.IsZero() {
+		s.DockCloseAMPM = s.DockCloseTime.AMPM()
+	}
 }
 
 //Consignee is where the shipment is going and what the shipment is
@@ -116,71 +175,41 @@ type Consignee struct {
 	FaxAreaCode   string `xml:"faxAreaCode"`
 	FaxNumber     string `xml:"faxNumber"`
 	Email         string `xml:"email"`
-	Hazmat        string `xml:"hazmat"`
-	Freezable     string `xml:"freezable"`
+	Hazmat        string `xml:"hazmat"`    //"Y" or "N"
+	Freezable     string `xml:"freezable"` //"Y" or "N"
 	Description   string `xml:"description"`
 }
 
 //SetProductionMode chooses the production url for use
+//Deprecated: build a Client with WithTestMode(false) instead. This replaces
+//the package's default Client, used by the other Set*/RequestPickup shims,
+//with an updated copy.
 func SetProductionMode(yes bool) {
-	if yes {
-		testMode = false
+	if !yes {
+		return
 	}
 
-	return
+	updated := *getDefaultClient()
+	updated.TestMode = false
+	defaultClient.Store(&updated)
 }
 
 //SetTimeout updates the timeout value to something the user sets
 //use this to increase the timeout if connecting to Ward is really slow
+//Deprecated: build a Client with WithTimeout() instead. This replaces the
+//package's default Client, used by the other Set*/RequestPickup shims, with
+//an updated copy.
 func SetTimeout(seconds time.Duration) {
-	timeout = time.Duration(seconds * time.Second)
-	return
+	updated := *getDefaultClient()
+	updated.Timeout = time.Duration(seconds * time.Second)
+	updated.HTTPClient = &http.Client{Timeout: updated.Timeout}
+	defaultClient.Store(&updated)
 }
 
-//RequestPickup performs the call to the ODFL API to schedule a pickup
-func (p *PickupRequest) RequestPickup() (responseData map[string]interface{}, err error) {
-	//convert the pickup request to an xml
-	xmlBytes, err := xml.Marshal(p)
-	if err != nil {
-		err = errors.Wrap(err, "odfl.RequestPickup - could not marshal xml")
-		return
-	}
-
-	//add xml attributes
-	p.SoapenvAttr = soapenv
-	p.PicAttr = pic
-
-	//set test mode
-	p.Shipper.TestFlag = testMode
-
-	//make the call to the ward API
-	//set a timeout since golang doesn't set one by default and we don't want this to hang forever
-	httpClient := http.Client{
-		Timeout: timeout,
-	}
-	res, err := httpClient.Post(odURL, "text/xml", bytes.NewReader(xmlBytes))
-	if err != nil {
-		err = errors.Wrap(err, "odfl.RequestPickup - could not make post request")
-		return
-	}
-
-	//read the response
-	body, err := ioutil.ReadAll(res.Body)
-	defer res.Body.Close()
-	if err != nil {
-		err = errors.Wrap(err, "odfl.RequestPickup - could not read response 1")
-		return
-	}
-
-	err = xml.Unmarshal(body, &responseData)
-	if err != nil {
-		err = errors.Wrap(err, "odfl.RequestPickup - could not read response 2")
-		return
-	}
-
-	log.Println(responseData)
-
-	//pickup request successful
-	//response data will have confirmation info
-	return
+//RequestPickup performs the call to the ODFL API to schedule a pickup,
+//using the package's default Client (SOAP transport, test mode on by
+//default - see SetProductionMode/SetTimeout).
+//Deprecated: build a Client and call (*Client).RequestPickup() instead.
+func (p *PickupRequest) RequestPickup() (response *PickupResponse, err error) {
+	return getDefaultClient().RequestPickup(p)
 }
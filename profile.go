@@ -0,0 +1,210 @@
+package odfl
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//defaultProfileJSON is a bundled snapshot of ODFL's service capabilities,
+//used by (*Client).Profile when ProfileSource isn't overridden. ODFL doesn't
+//expose a first-class profile/capabilities endpoint (nothing analogous to an
+//OFX profile request), so this file stands in for one - update it and ship a
+//new release to pick up carrier changes, or set ProfileSource to load your
+//own copy without a code release.
+//go:embed profile_default.json
+var defaultProfileJSON []byte
+
+//HazmatLane describes whether ODFL accepts hazmat shipments between two
+//countries.
+type HazmatLane struct {
+	OriginCountry      string `json:"originCountry"`
+	DestinationCountry string `json:"destinationCountry"`
+	Accepted           bool   `json:"accepted"`
+}
+
+//HandlingUnitLimits are the weight/dimension limits ODFL enforces per
+//handling unit (skid, crate, etc.).
+type HandlingUnitLimits struct {
+	MaxWeight float64 `json:"maxWeight"` //lbs
+	MaxLength float64 `json:"maxLength"` //inches
+	MaxWidth  float64 `json:"maxWidth"`  //inches
+	MaxHeight float64 `json:"maxHeight"` //inches
+}
+
+//Profile is a snapshot of the services and limits ODFL currently supports,
+//used to validate a request locally before sending it, instead of finding
+//out it was rejected after a round-trip.
+type Profile struct {
+	UnitTypeCodes      []string           `json:"unitTypeCodes"`
+	CountryCodes       []string           `json:"countryCodes"`
+	AccessorialCodes   []string           `json:"accessorialCodes"` //ex: "HAZMAT", "FREEZABLE", "LIFTGATE"
+	HazmatLanes        []HazmatLane       `json:"hazmatLanes"`
+	HandlingUnitLimits HandlingUnitLimits `json:"handlingUnitLimits"`
+}
+
+//hasAccessorial reports whether code is one of p's known accessorial codes.
+func (p *Profile) hasAccessorial(code string) bool {
+	for _, c := range p.AccessorialCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+//hasCountry reports whether code is one of p's known country codes.
+func (p *Profile) hasCountry(code string) bool {
+	for _, c := range p.CountryCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+//hasUnitType reports whether code is one of p's known unit type codes.
+func (p *Profile) hasUnitType(code string) bool {
+	for _, c := range p.UnitTypeCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+//hazmatAccepted reports whether p's hazmat lanes allow a hazmat shipment
+//from origin to destination. An unlisted lane is treated as not accepted,
+//so an out-of-date bundled profile fails closed instead of letting an
+//unsupported hazmat lane through.
+func (p *Profile) hazmatAccepted(origin, destination string) bool {
+	for _, lane := range p.HazmatLanes {
+		if lane.OriginCountry == origin && lane.DestinationCountry == destination {
+			return lane.Accepted
+		}
+	}
+
+	return false
+}
+
+//profileCache holds the last Profile a Client fetched, shared across copies
+//of that Client (see SetProductionMode/SetTimeout) so a round-trip to load
+//it is only paid once per TTL regardless of which snapshot a caller holds.
+type profileCache struct {
+	mu        sync.Mutex
+	profile   *Profile
+	fetchedAt time.Time
+}
+
+//defaultProfile parses the bundled profile_default.json.
+func defaultProfile() (*Profile, error) {
+	p := &Profile{}
+	if err := json.Unmarshal(defaultProfileJSON, p); err != nil {
+		return nil, errors.Wrap(err, "odfl: could not parse bundled default profile")
+	}
+
+	return p, nil
+}
+
+//Profile returns ODFL's current service capabilities, fetching and caching
+//them for ProfileTTL (see WithProfileTTL). Since ODFL has no first-class
+//profile endpoint, the default ProfileSource just parses the bundled
+//profile_default.json - set ProfileSource (WithProfileSource) to load a
+//carrier endpoint or an updated file instead.
+func (c *Client) Profile(ctx context.Context) (*Profile, error) {
+	c.profileCache.mu.Lock()
+	defer c.profileCache.mu.Unlock()
+
+	if c.profileCache.profile != nil && time.Since(c.profileCache.fetchedAt) < c.ProfileTTL {
+		return c.profileCache.profile, nil
+	}
+
+	source := c.ProfileSource
+	if source == nil {
+		source = func(context.Context) (*Profile, error) {
+			return defaultProfile()
+		}
+	}
+
+	profile, err := source(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "odfl.Profile - could not load profile")
+	}
+
+	c.profileCache.profile = profile
+	c.profileCache.fetchedAt = time.Now()
+	return profile, nil
+}
+
+//isHazmatYes reports whether a Consignee.Hazmat/Freezable "Y"/"N" flag is
+//set to "Y", case-insensitively, treating anything else (including "N" and
+//an empty string) as not set.
+func isHazmatYes(flag string) bool {
+	return strings.EqualFold(flag, "Y")
+}
+
+//Validate checks p against c's cached Profile, rejecting a missing
+//PickupDate/PickupTime, an unknown UnitType, an out-of-range Weight, an
+//unsupported Country, an unrequestable Hazmat/Freezable accessorial, or a
+//hazmat shipment on a lane ODFL doesn't accept, before the request is sent.
+func (p *PickupRequest) Validate(ctx context.Context, c *Client) error {
+	profile, err := c.Profile(ctx)
+	if err != nil {
+		return errors.Wrap(err, "odfl: PickupRequest failed validation - could not load profile")
+	}
+
+	var problems []string
+
+	if p.Shipper.PickupDate.IsZero() {
+		problems = append(problems, "shipper.pickupDate is required")
+	}
+
+	if p.Shipper.PickupTime.IsZero() {
+		problems = append(problems, "shipper.pickupTime is required")
+	}
+
+	if p.Shipper.Country != "" && !profile.hasCountry(p.Shipper.Country) {
+		problems = append(problems, errors.Errorf("shipper.country %q is not a supported country", p.Shipper.Country).Error())
+	}
+
+	if p.Consignee.Country != "" && !profile.hasCountry(p.Consignee.Country) {
+		problems = append(problems, errors.Errorf("consignee.country %q is not a supported country", p.Consignee.Country).Error())
+	}
+
+	if p.Consignee.UnitType != "" && !profile.hasUnitType(p.Consignee.UnitType) {
+		problems = append(problems, errors.Errorf("consignee.unitType %q is not a supported unit type", p.Consignee.UnitType).Error())
+	}
+
+	maxWeight := profile.HandlingUnitLimits.MaxWeight * float64(p.Consignee.HandlingUnits)
+	if maxWeight > 0 && p.Consignee.Weight > maxWeight {
+		problems = append(problems, errors.Errorf("consignee.weight %v exceeds the %v lb limit for %d handling unit(s)", p.Consignee.Weight, maxWeight, p.Consignee.HandlingUnits).Error())
+	}
+
+	if isHazmatYes(p.Consignee.Hazmat) {
+		if !profile.hasAccessorial("HAZMAT") {
+			problems = append(problems, "hazmat is not a supported accessorial")
+		}
+
+		if !profile.hazmatAccepted(p.Shipper.Country, p.Consignee.Country) {
+			problems = append(problems, errors.Errorf("hazmat shipments from %q to %q are not accepted", p.Shipper.Country, p.Consignee.Country).Error())
+		}
+	}
+
+	if isHazmatYes(p.Consignee.Freezable) && !profile.hasAccessorial("FREEZABLE") {
+		problems = append(problems, "freezable is not a supported accessorial")
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("odfl: PickupRequest failed validation: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package odfl
+
+import (
+	"context"
+	"testing"
+)
+
+func validPickupRequest() *PickupRequest {
+	date, err := ParseDate("2024-03-05")
+	if err != nil {
+		panic(err)
+	}
+
+	timeOfDay, err := ParseTimeOfDay("09:00")
+	if err != nil {
+		panic(err)
+	}
+
+	p := &PickupRequest{}
+	p.Shipper.Country = "USA"
+	p.Shipper.PickupDate = date
+	p.Shipper.PickupTime = timeOfDay
+	p.Consignee.Country = "CAN"
+	p.Consignee.UnitType = "SKID"
+	p.Consignee.HandlingUnits = 2
+	p.Consignee.Weight = 500
+	return p
+}
+
+func TestPickupRequestValidate(t *testing.T) {
+	c := NewClient()
+	ctx := context.Background()
+
+	if err := validPickupRequest().Validate(ctx, c); err != nil {
+		t.Fatalf("Validate() on a valid request returned an error: %v", err)
+	}
+
+	t.Run("missing pickup date", func(t *testing.T) {
+		p := validPickupRequest()
+		p.Shipper.PickupDate = Date{}
+		if err := p.Validate(ctx, c); err == nil {
+			t.Error("Validate() expected an error for a missing pickupDate, got nil")
+		}
+	})
+
+	t.Run("missing pickup time", func(t *testing.T) {
+		p := validPickupRequest()
+		p.Shipper.PickupTime = TimeOfDay{}
+		if err := p.Validate(ctx, c); err == nil {
+			t.Error("Validate() expected an error for a missing pickupTime, got nil")
+		}
+	})
+
+	t.Run("unknown unit type", func(t *testing.T) {
+		p := validPickupRequest()
+		p.Consignee.UnitType = "NOTREAL"
+		if err := p.Validate(ctx, c); err == nil {
+			t.Error("Validate() expected an error for an unknown unitType, got nil")
+		}
+	})
+
+	t.Run("unsupported country", func(t *testing.T) {
+		p := validPickupRequest()
+		p.Consignee.Country = "ZZZ"
+		if err := p.Validate(ctx, c); err == nil {
+			t.Error("Validate() expected an error for an unsupported country, got nil")
+		}
+	})
+
+	t.Run("weight over the per handling unit limit", func(t *testing.T) {
+		p := validPickupRequest()
+		p.Consignee.HandlingUnits = 1
+		p.Consignee.Weight = 999999
+		if err := p.Validate(ctx, c); err == nil {
+			t.Error("Validate() expected an error for an over-limit weight, got nil")
+		}
+	})
+
+	t.Run("hazmat on an unaccepted lane", func(t *testing.T) {
+		p := validPickupRequest()
+		p.Shipper.Country = "USA"
+		p.Consignee.Country = "MEX"
+		p.Consignee.Hazmat = "Y"
+		if err := p.Validate(ctx, c); err == nil {
+			t.Error("Validate() expected an error for hazmat on an unaccepted lane, got nil")
+		}
+	})
+
+	t.Run("hazmat flag of N is not treated as hazmat", func(t *testing.T) {
+		p := validPickupRequest()
+		p.Shipper.Country = "USA"
+		p.Consignee.Country = "MEX"
+		p.Consignee.Hazmat = "N"
+		if err := p.Validate(ctx, c); err != nil {
+			t.Errorf("Validate() with hazmat=%q returned an error: %v", p.Consignee.Hazmat, err)
+		}
+	})
+}
+
+func TestProfileDefault(t *testing.T) {
+	profile, err := defaultProfile()
+	if err != nil {
+		t.Fatalf("defaultProfile() returned an error: %v", err)
+	}
+
+	if !profile.hasUnitType("SKID") {
+		t.Error(`defaultProfile() unitTypeCodes missing "SKID"`)
+	}
+
+	if !profile.hasCountry("USA") {
+		t.Error(`defaultProfile() countryCodes missing "USA"`)
+	}
+
+	if !profile.hasAccessorial("HAZMAT") {
+		t.Error(`defaultProfile() accessorialCodes missing "HAZMAT"`)
+	}
+}
+
+func TestClientProfileIsCached(t *testing.T) {
+	c := NewClient()
+
+	calls := 0
+	c.ProfileSource = func(context.Context) (*Profile, error) {
+		calls++
+		return defaultProfile()
+	}
+
+	ctx := context.Background()
+	if _, err := c.Profile(ctx); err != nil {
+		t.Fatalf("Profile() returned an error: %v", err)
+	}
+	if _, err := c.Profile(ctx); err != nil {
+		t.Fatalf("Profile() returned an error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("ProfileSource was called %d times, want 1 (second call should hit the cache)", calls)
+	}
+}
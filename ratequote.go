@@ -0,0 +1,236 @@
+package odfl
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+//RateQuoteURLTest and RateQuoteURLProd are the SOAP endpoints rate quote
+//requests are posted to when a Client's RateQuoteURL is unset - RateQuoteURLTest
+//unless TestMode is false (or SetProductionMode(true) was called, for the
+//package-level default Client).
+var (
+	RateQuoteURLTest = "http://www.odfl.com/wsRateQuote_v1b/services/ODRateQuoteSOAPTest"
+	RateQuoteURLProd = "http://www.odfl.com/wsRateQuote_v1b/services/ODRateQuoteSOAP"
+)
+
+//rate is the xml namespace for the rate quote service
+var rate = "http://rate.ws.odfl.com"
+
+//RateQuoteRequest is the main body of the rate quote xml request
+type RateQuoteRequest struct {
+	XMLName xml.Name `xml:"soapenv:Envelope"`
+
+	SoapenvAttr string `xml:"xmlns:soapenv,attr"`
+	RateAttr    string `xml:"xmlns:rate,attr"`
+
+	OriginPostalCode      string           `xml:"soapenv:Header>soapenv:Body>rate:rateQuoteRequest>originPostalCode"`
+	DestinationPostalCode string           `xml:"soapenv:Header>soapenv:Body>rate:rateQuoteRequest>destinationPostalCode"`
+	Items                 []RateQuoteItem  `xml:"soapenv:Header>soapenv:Body>rate:rateQuoteRequest>items>item"`
+	Accessorials          RateAccessorials `xml:"soapenv:Header>soapenv:Body>rate:rateQuoteRequest>accessorials"`
+}
+
+//RateQuoteItem is one freight class/weight combination being quoted
+type RateQuoteItem struct {
+	FreightClass string  `xml:"freightClass" json:"freightClass"` //ex: "70", "92.5"
+	Weight       float64 `xml:"weight" json:"weight"`             //lbs
+	Hazmat       bool    `xml:"hazmat" json:"hazmat"`
+}
+
+//RateAccessorials are the extra services being requested as part of the quote
+type RateAccessorials struct {
+	Liftgate       bool `xml:"liftgate" json:"liftgate"`
+	Residential    bool `xml:"residential" json:"residential"`
+	InsideDelivery bool `xml:"insideDelivery" json:"insideDelivery"`
+}
+
+//RateQuoteResponse is the parsed response from the rate quote endpoint
+type RateQuoteResponse struct {
+	TotalCharge  float64             `xml:"Body>rateQuoteResponse>totalCharge" json:"totalCharge"`
+	TransitDays  int                 `xml:"Body>rateQuoteResponse>transitDays" json:"transitDays"`
+	ServiceLevel string              `xml:"Body>rateQuoteResponse>serviceLevel" json:"serviceLevel"`
+	LineItems    []RateQuoteLineItem `xml:"Body>rateQuoteResponse>lineItems>lineItem" json:"lineItems"`
+}
+
+//RateQuoteLineItem is one charge making up the total on a rate quote
+type RateQuoteLineItem struct {
+	Description string  `xml:"description" json:"description"`
+	Charge      float64 `xml:"charge" json:"charge"`
+}
+
+//rateQuoteRequestJSON mirrors RateQuoteRequest's top-level fields with json
+//tags, since RateQuoteRequest's struct tags are xml paths into the SOAP
+//envelope.
+type rateQuoteRequestJSON struct {
+	OriginPostalCode      string           `json:"originPostalCode"`
+	DestinationPostalCode string           `json:"destinationPostalCode"`
+	Items                 []RateQuoteItem  `json:"items"`
+	Accessorials          RateAccessorials `json:"accessorials"`
+}
+
+//resolveRateQuoteURL returns the endpoint rate quote requests should be
+//posted to: c.RateQuoteURL if set, otherwise RateQuoteURLTest/
+//RateQuoteURLProd (by TestMode) for TransportSOAP. REST has no default -
+//ODFL hasn't published one - so it's an error to leave RateQuoteURL unset
+//with TransportREST.
+func (c *Client) resolveRateQuoteURL() (string, error) {
+	if c.RateQuoteURL != "" {
+		return c.RateQuoteURL, nil
+	}
+	if c.Transport == TransportSOAP {
+		if c.TestMode {
+			return RateQuoteURLTest, nil
+		}
+		return RateQuoteURLProd, nil
+	}
+	return "", errors.New("odfl: RateQuoteURL must be set (via WithRateQuoteURL) when using TransportREST")
+}
+
+//GetRateQuote gets a rate quote using whichever transport the Client was
+//configured with.
+func (c *Client) GetRateQuote(r *RateQuoteRequest) (quote *RateQuoteResponse, err error) {
+	url, err := c.resolveRateQuoteURL()
+	if err != nil {
+		return
+	}
+
+	if c.Transport == TransportSOAP {
+		return c.getRateQuoteSOAP(r, url)
+	}
+
+	return c.getRateQuoteREST(r, url)
+}
+
+//getRateQuoteSOAP posts the same xml SOAP envelope used by the package-level
+//GetRateQuote, against url.
+func (c *Client) getRateQuoteSOAP(r *RateQuoteRequest, url string) (quote *RateQuoteResponse, err error) {
+	r.SoapenvAttr = soapenv
+	r.RateAttr = rate
+
+	xmlBytes, err := xml.Marshal(r)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteSOAP - could not marshal xml")
+		return
+	}
+
+	res, err := c.HTTPClient.Post(url, "text/xml", bytes.NewReader(xmlBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteSOAP - could not make post request")
+		return
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteSOAP - could not read response")
+		return
+	}
+
+	quote = &RateQuoteResponse{}
+	err = xml.Unmarshal(body, quote)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteSOAP - could not unmarshal response")
+		return
+	}
+
+	return
+}
+
+//getRateQuoteREST marshals r to json and posts it to url with basic auth,
+//unmarshaling the result into a RateQuoteResponse.
+func (c *Client) getRateQuoteREST(r *RateQuoteRequest, url string) (quote *RateQuoteResponse, err error) {
+	jsonBytes, err := json.Marshal(rateQuoteRequestJSON{
+		OriginPostalCode:      r.OriginPostalCode,
+		DestinationPostalCode: r.DestinationPostalCode,
+		Items:                 r.Items,
+		Accessorials:          r.Accessorials,
+	})
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteREST - could not marshal json")
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteREST - could not build request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.Credentials.Username, c.Credentials.Password)
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteREST - could not make post request")
+		return
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteREST - could not read response")
+		return
+	}
+
+	quote = &RateQuoteResponse{}
+	err = json.Unmarshal(body, quote)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.getRateQuoteREST - could not unmarshal response")
+		return
+	}
+
+	return
+}
+
+//GetRateQuote performs the call to the ODFL API to get a rate quote, using
+//the package's default Client (SOAP transport - see SetProductionMode/
+//SetTimeout).
+//Deprecated: build a Client and call (*Client).GetRateQuote() instead, which
+//also supports TransportREST and targeting a non-default account/environment.
+func (r *RateQuoteRequest) GetRateQuote() (quote *RateQuoteResponse, err error) {
+	//add xml attributes
+	r.SoapenvAttr = soapenv
+	r.RateAttr = rate
+
+	//convert the rate quote request to an xml
+	xmlBytes, err := xml.Marshal(r)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.GetRateQuote - could not marshal xml")
+		return
+	}
+
+	//pick the test or production endpoint, and the timeout, from the
+	//package's default Client (see SetProductionMode/SetTimeout)
+	defaultC := getDefaultClient()
+	url := RateQuoteURLTest
+	if !defaultC.TestMode {
+		url = RateQuoteURLProd
+	}
+
+	res, err := defaultC.HTTPClient.Post(url, "text/xml", bytes.NewReader(xmlBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.GetRateQuote - could not make post request")
+		return
+	}
+
+	//read the response
+	body, err := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	if err != nil {
+		err = errors.Wrap(err, "odfl.GetRateQuote - could not read response 1")
+		return
+	}
+
+	quote = &RateQuoteResponse{}
+	err = xml.Unmarshal(body, quote)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.GetRateQuote - could not read response 2")
+		return
+	}
+
+	return
+}
@@ -0,0 +1,64 @@
+package odfl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveRateQuoteURL(t *testing.T) {
+	t.Run("defaults to RateQuoteURLTest in test mode", func(t *testing.T) {
+		c := NewClient()
+		url, err := c.resolveRateQuoteURL()
+		if err != nil {
+			t.Fatalf("resolveRateQuoteURL returned an error: %v", err)
+		}
+		if url != RateQuoteURLTest {
+			t.Errorf("resolveRateQuoteURL() = %q, want %q", url, RateQuoteURLTest)
+		}
+	})
+
+	t.Run("defaults to RateQuoteURLProd outside test mode", func(t *testing.T) {
+		c := NewClient(WithTestMode(false))
+		url, err := c.resolveRateQuoteURL()
+		if err != nil {
+			t.Fatalf("resolveRateQuoteURL returned an error: %v", err)
+		}
+		if url != RateQuoteURLProd {
+			t.Errorf("resolveRateQuoteURL() = %q, want %q", url, RateQuoteURLProd)
+		}
+	})
+
+	t.Run("REST requires an explicit RateQuoteURL", func(t *testing.T) {
+		c := NewClient(WithTransport(TransportREST))
+		if _, err := c.resolveRateQuoteURL(); err == nil {
+			t.Error("resolveRateQuoteURL() expected an error for TransportREST with no RateQuoteURL, got nil")
+		}
+	})
+}
+
+func TestGetRateQuoteREST(t *testing.T) {
+	var gotAuth bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, gotAuth = r.BasicAuth()
+		w.Write([]byte(`{"totalCharge":123.45}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(
+		WithTransport(TransportREST),
+		WithRateQuoteURL(ts.URL),
+		WithCredentials(Credentials{Username: "user", Password: "pass"}),
+	)
+
+	quote, err := c.GetRateQuote(&RateQuoteRequest{})
+	if err != nil {
+		t.Fatalf("GetRateQuote returned an error: %v", err)
+	}
+	if !gotAuth {
+		t.Error("GetRateQuote did not send basic auth credentials")
+	}
+	if quote.TotalCharge != 123.45 {
+		t.Errorf("TotalCharge = %v, want %v", quote.TotalCharge, 123.45)
+	}
+}
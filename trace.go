@@ -0,0 +1,223 @@
+package odfl
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+//TraceURLTest and TraceURLProd are the SOAP endpoints trace requests are
+//posted to when a Client's TraceURL is unset - TraceURLTest unless TestMode
+//is false (or SetProductionMode(true) was called, for the package-level
+//default Client).
+var (
+	TraceURLTest = "http://www.odfl.com/wsTrace_v1b/services/ODTraceSOAPTest"
+	TraceURLProd = "http://www.odfl.com/wsTrace_v1b/services/ODTraceSOAP"
+)
+
+//trace is the xml namespace for the shipment tracing service
+var trace = "http://trace.ws.odfl.com"
+
+//TraceRequest looks up a shipment's status, keyed by either a PRO number
+//or a BOL number plus the origin postal code
+type TraceRequest struct {
+	XMLName xml.Name `xml:"soapenv:Envelope"`
+
+	SoapenvAttr string `xml:"xmlns:soapenv,attr"`
+	TraceAttr   string `xml:"xmlns:trace,attr"`
+
+	//ProNumber is used alone to trace a shipment
+	ProNumber string `xml:"soapenv:Header>soapenv:Body>trace:traceRequest>proNumber"`
+
+	//BOLNumber and OriginPostalCode are used together to trace a shipment
+	//when the PRO number isn't known
+	BOLNumber        string `xml:"soapenv:Header>soapenv:Body>trace:traceRequest>bolNumber"`
+	OriginPostalCode string `xml:"soapenv:Header>soapenv:Body>trace:traceRequest>originPostalCode"`
+}
+
+//TraceResponse is the parsed response from the shipment tracing endpoint
+type TraceResponse struct {
+	ProNumber string       `xml:"Body>traceResponse>proNumber" json:"proNumber"`
+	Events    []TraceEvent `xml:"Body>traceResponse>events>event" json:"events"`
+}
+
+//TraceEvent is a single status update in a shipment's history
+type TraceEvent struct {
+	Status      string `xml:"status" json:"status"` //ex: "Picked Up", "In Transit", "Delivered"
+	Description string `xml:"description" json:"description"`
+	Location    string `xml:"location" json:"location"`   //city, state of the event
+	Timestamp   string `xml:"timestamp" json:"timestamp"` //yyyymmddhhmmss
+}
+
+//traceRequestJSON mirrors TraceRequest's top-level fields with json tags,
+//since TraceRequest's struct tags are xml paths into the SOAP envelope.
+type traceRequestJSON struct {
+	ProNumber        string `json:"proNumber,omitempty"`
+	BOLNumber        string `json:"bolNumber,omitempty"`
+	OriginPostalCode string `json:"originPostalCode,omitempty"`
+}
+
+//resolveTraceURL returns the endpoint trace requests should be posted to:
+//c.TraceURL if set, otherwise TraceURLTest/TraceURLProd (by TestMode) for
+//TransportSOAP. REST has no default - ODFL hasn't published one - so it's
+//an error to leave TraceURL unset with TransportREST.
+func (c *Client) resolveTraceURL() (string, error) {
+	if c.TraceURL != "" {
+		return c.TraceURL, nil
+	}
+	if c.Transport == TransportSOAP {
+		if c.TestMode {
+			return TraceURLTest, nil
+		}
+		return TraceURLProd, nil
+	}
+	return "", errors.New("odfl: TraceURL must be set (via WithTraceURL) when using TransportREST")
+}
+
+//TraceShipment looks up a shipment's status using whichever transport the
+//Client was configured with.
+func (c *Client) TraceShipment(t *TraceRequest) (status *TraceResponse, err error) {
+	url, err := c.resolveTraceURL()
+	if err != nil {
+		return
+	}
+
+	if c.Transport == TransportSOAP {
+		return c.traceShipmentSOAP(t, url)
+	}
+
+	return c.traceShipmentREST(t, url)
+}
+
+//traceShipmentSOAP posts the same xml SOAP envelope used by the
+//package-level TraceShipment, against url.
+func (c *Client) traceShipmentSOAP(t *TraceRequest, url string) (status *TraceResponse, err error) {
+	t.SoapenvAttr = soapenv
+	t.TraceAttr = trace
+
+	xmlBytes, err := xml.Marshal(t)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentSOAP - could not marshal xml")
+		return
+	}
+
+	res, err := c.HTTPClient.Post(url, "text/xml", bytes.NewReader(xmlBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentSOAP - could not make post request")
+		return
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentSOAP - could not read response")
+		return
+	}
+
+	status = &TraceResponse{}
+	err = xml.Unmarshal(body, status)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentSOAP - could not unmarshal response")
+		return
+	}
+
+	return
+}
+
+//traceShipmentREST marshals t to json and posts it to the Client's REST
+//endpoint with basic auth, unmarshaling the result into a TraceResponse.
+func (c *Client) traceShipmentREST(t *TraceRequest, url string) (status *TraceResponse, err error) {
+	jsonBytes, err := json.Marshal(traceRequestJSON{
+		ProNumber:        t.ProNumber,
+		BOLNumber:        t.BOLNumber,
+		OriginPostalCode: t.OriginPostalCode,
+	})
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentREST - could not marshal json")
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentREST - could not build request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.Credentials.Username, c.Credentials.Password)
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentREST - could not make post request")
+		return
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentREST - could not read response")
+		return
+	}
+
+	status = &TraceResponse{}
+	err = json.Unmarshal(body, status)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.traceShipmentREST - could not unmarshal response")
+		return
+	}
+
+	return
+}
+
+//TraceShipment performs the call to the ODFL API to trace a shipment's
+//status, using the package's default Client (SOAP transport - see
+//SetProductionMode/SetTimeout).
+//Deprecated: build a Client and call (*Client).TraceShipment() instead,
+//which also supports TransportREST and targeting a non-default account/
+//environment.
+func (t *TraceRequest) TraceShipment() (status *TraceResponse, err error) {
+	//add xml attributes
+	t.SoapenvAttr = soapenv
+	t.TraceAttr = trace
+
+	//convert the trace request to an xml
+	xmlBytes, err := xml.Marshal(t)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.TraceShipment - could not marshal xml")
+		return
+	}
+
+	//pick the test or production endpoint, and the timeout, from the
+	//package's default Client (see SetProductionMode/SetTimeout)
+	defaultC := getDefaultClient()
+	url := TraceURLTest
+	if !defaultC.TestMode {
+		url = TraceURLProd
+	}
+
+	res, err := defaultC.HTTPClient.Post(url, "text/xml", bytes.NewReader(xmlBytes))
+	if err != nil {
+		err = errors.Wrap(err, "odfl.TraceShipment - could not make post request")
+		return
+	}
+
+	//read the response
+	body, err := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	if err != nil {
+		err = errors.Wrap(err, "odfl.TraceShipment - could not read response 1")
+		return
+	}
+
+	status = &TraceResponse{}
+	err = xml.Unmarshal(body, status)
+	if err != nil {
+		err = errors.Wrap(err, "odfl.TraceShipment - could not read response 2")
+		return
+	}
+
+	return
+}
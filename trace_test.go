@@ -0,0 +1,64 @@
+package odfl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveTraceURL(t *testing.T) {
+	t.Run("defaults to TraceURLTest in test mode", func(t *testing.T) {
+		c := NewClient()
+		url, err := c.resolveTraceURL()
+		if err != nil {
+			t.Fatalf("resolveTraceURL returned an error: %v", err)
+		}
+		if url != TraceURLTest {
+			t.Errorf("resolveTraceURL() = %q, want %q", url, TraceURLTest)
+		}
+	})
+
+	t.Run("defaults to TraceURLProd outside test mode", func(t *testing.T) {
+		c := NewClient(WithTestMode(false))
+		url, err := c.resolveTraceURL()
+		if err != nil {
+			t.Fatalf("resolveTraceURL returned an error: %v", err)
+		}
+		if url != TraceURLProd {
+			t.Errorf("resolveTraceURL() = %q, want %q", url, TraceURLProd)
+		}
+	})
+
+	t.Run("REST requires an explicit TraceURL", func(t *testing.T) {
+		c := NewClient(WithTransport(TransportREST))
+		if _, err := c.resolveTraceURL(); err == nil {
+			t.Error("resolveTraceURL() expected an error for TransportREST with no TraceURL, got nil")
+		}
+	})
+}
+
+func TestTraceShipmentREST(t *testing.T) {
+	var gotAuth bool
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, gotAuth = r.BasicAuth()
+		w.Write([]byte(`{"proNumber":"PRO123"}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(
+		WithTransport(TransportREST),
+		WithTraceURL(ts.URL),
+		WithCredentials(Credentials{Username: "user", Password: "pass"}),
+	)
+
+	status, err := c.TraceShipment(&TraceRequest{ProNumber: "PRO123"})
+	if err != nil {
+		t.Fatalf("TraceShipment returned an error: %v", err)
+	}
+	if !gotAuth {
+		t.Error("TraceShipment did not send basic auth credentials")
+	}
+	if status.ProNumber != "PRO123" {
+		t.Errorf("ProNumber = %q, want %q", status.ProNumber, "PRO123")
+	}
+}